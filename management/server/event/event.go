@@ -1,6 +1,11 @@
 package event
 
-import "time"
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
 
 const (
 	// DeviceEvent describes an event that happened of a device (e.g, connected/disconnected)
@@ -21,18 +26,80 @@ const (
 	UserJoinedOperationMessage     string = "New user joined"
 )
 
-// MessageForOperation returns a string message for an Operation
+// Severity describes how important an Event is, mirroring familiar syslog/logrus levels so sinks
+// (syslog, webhook alerting, etc.) can map it directly onto their own levels.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarn     Severity = "warn"
+	SeverityError    Severity = "error"
+	SeverityCritical Severity = "critical"
+)
+
+// opDescriptor holds everything the registry knows about an Operation.
+type opDescriptor struct {
+	name     string
+	template string
+	severity Severity
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[Operation]opDescriptor{}
+)
+
+// RegisterOperation registers a new Operation so subsystems outside of this package (routes,
+// groups, nameservers, setup keys, ...) can define their own event types without this package
+// knowing about them ahead of time. code must be unique; registering an already-registered code
+// panics, since that indicates two subsystems picked the same constant.
+func RegisterOperation(code Operation, name, template string, severity Severity) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry[code]; ok {
+		panic(fmt.Sprintf("event: operation code %d already registered as %q", code, registry[code].name))
+	}
+
+	registry[code] = opDescriptor{
+		name:     name,
+		template: template,
+		severity: severity,
+	}
+}
+
+func init() {
+	RegisterOperation(AddPeerByUserOperation, "AddPeerByUserOperation", AddPeerByUserOperationMessage, SeverityInfo)
+	RegisterOperation(AddPeerWithKeyOperation, "AddPeerWithKeyOperation", AddPeerWithKeyOperationMessage, SeverityInfo)
+	RegisterOperation(UserJoinedOperation, "UserJoinedOperation", UserJoinedOperationMessage, SeverityInfo)
+}
+
+// MessageForOperation returns a string message for an Operation.
 func MessageForOperation(op Operation) string {
-	switch op {
-	case AddPeerByUserOperation:
-		return AddPeerByUserOperationMessage
-	case AddPeerWithKeyOperation:
-		return AddPeerWithKeyOperationMessage
-	case UserJoinedOperation:
-		return UserJoinedOperationMessage
-	default:
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	desc, ok := registry[op]
+	if !ok {
 		return "UNKNOWN_OPERATION"
 	}
+	return desc.template
+}
+
+// SeverityForOperation returns the registered Severity for op, defaulting to SeverityInfo for
+// operations that were registered without one and SeverityError for unregistered codes.
+func SeverityForOperation(op Operation) Severity {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	desc, ok := registry[op]
+	if !ok {
+		return SeverityError
+	}
+	if desc.severity == "" {
+		return SeverityInfo
+	}
+	return desc.severity
 }
 
 // Type of the Event
@@ -45,6 +112,9 @@ type Operation int
 type Store interface {
 	// Save an event in the store
 	Save(event Event) (*Event, error)
+	// SaveCtx behaves like Save but allows the caller to carry a context (e.g. for request IDs
+	// or cancellation) down to sinks that can make use of it.
+	SaveCtx(ctx context.Context, event Event) (*Event, error)
 	// Get returns "limit" number of events from the "offset" index ordered descending or ascending by a timestamp
 	Get(accountID string, offset, limit int, descending bool) ([]Event, error)
 	// Close the sink flushing events if necessary
@@ -69,17 +139,36 @@ type Event struct {
 	TargetID string
 	// AccountID where event happened
 	AccountID string
+	// Severity of the event, defaults to SeverityInfo when left empty
+	Severity Severity
+	// Meta carries operation-specific attributes (e.g. route network, group name) that don't
+	// warrant their own column
+	Meta map[string]any
+	// IPAddress the request that triggered the event originated from, if known
+	IPAddress string
+	// UserAgent of the client that triggered the event, if known
+	UserAgent string
 }
 
 // Copy the event
 func (e *Event) Copy() *Event {
+	meta := make(map[string]any, len(e.Meta))
+	for k, v := range e.Meta {
+		meta[k] = v
+	}
+
 	return &Event{
-		Timestamp:  e.Timestamp,
-		Operation:  e.Operation,
-		ID:         e.ID,
-		Type:       e.Type,
-		ModifierID: e.ModifierID,
-		TargetID:   e.TargetID,
-		AccountID:  e.AccountID,
+		Timestamp:     e.Timestamp,
+		Operation:     e.Operation,
+		OperationCode: e.OperationCode,
+		ID:            e.ID,
+		Type:          e.Type,
+		ModifierID:    e.ModifierID,
+		TargetID:      e.TargetID,
+		AccountID:     e.AccountID,
+		Severity:      e.Severity,
+		Meta:          meta,
+		IPAddress:     e.IPAddress,
+		UserAgent:     e.UserAgent,
 	}
 }
@@ -0,0 +1,144 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const sinkQueueSize = 256
+
+// MultiStore fans an event out to multiple Store sinks. Each sink is fed through its own bounded
+// queue so a slow or stuck sink (e.g. a webhook that's timing out) cannot block the others or the
+// caller of Save/SaveCtx; when a sink's queue is full, the event is dropped for that sink only and
+// a counter is incremented.
+type MultiStore struct {
+	primary Store
+	sinks   []*queuedSink
+
+	wg sync.WaitGroup
+}
+
+type queuedSink struct {
+	name    string
+	sink    Store
+	queue   chan queuedEvent
+	dropped uint64
+	mu      sync.Mutex // guards dropped and closed, and is held across the send in SaveCtx so Close can't close the queue mid-send
+	closed  bool
+}
+
+type queuedEvent struct {
+	ctx   context.Context
+	event Event
+}
+
+// NewMultiStore creates a MultiStore that stores to primary (used to satisfy Get) and
+// additionally fans every save out to the named sinks.
+func NewMultiStore(primary Store, sinks map[string]Store) *MultiStore {
+	m := &MultiStore{primary: primary}
+
+	for name, sink := range sinks {
+		qs := &queuedSink{
+			name:  name,
+			sink:  sink,
+			queue: make(chan queuedEvent, sinkQueueSize),
+		}
+		m.sinks = append(m.sinks, qs)
+
+		m.wg.Add(1)
+		go m.drain(qs)
+	}
+
+	return m
+}
+
+func (m *MultiStore) drain(qs *queuedSink) {
+	defer m.wg.Done()
+	for qe := range qs.queue {
+		if _, err := qs.sink.SaveCtx(qe.ctx, qe.event); err != nil {
+			log.Errorf("failed to save event to sink %s: %s", qs.name, err)
+		}
+	}
+}
+
+// Save stores event in the primary store and fans it out to every sink.
+func (m *MultiStore) Save(event Event) (*Event, error) {
+	return m.SaveCtx(context.Background(), event)
+}
+
+// SaveCtx stores event in the primary store and fans it out to every sink. A sink whose queue is
+// full does not block the caller or other sinks; the event is dropped for that sink and its drop
+// counter is incremented.
+func (m *MultiStore) SaveCtx(ctx context.Context, event Event) (*Event, error) {
+	saved, err := m.primary.SaveCtx(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, qs := range m.sinks {
+		qs.mu.Lock()
+		if qs.closed {
+			qs.mu.Unlock()
+			continue
+		}
+
+		select {
+		case qs.queue <- queuedEvent{ctx: ctx, event: *saved}:
+			qs.mu.Unlock()
+		default:
+			qs.dropped++
+			qs.mu.Unlock()
+			log.Warnf("sink %s queue full, dropping event for account %s", qs.name, event.AccountID)
+		}
+	}
+
+	return saved, nil
+}
+
+// Get delegates to the primary store.
+func (m *MultiStore) Get(accountID string, offset, limit int, descending bool) ([]Event, error) {
+	return m.primary.Get(accountID, offset, limit, descending)
+}
+
+// DroppedForSink returns the number of events dropped for the named sink because its queue was
+// full.
+func (m *MultiStore) DroppedForSink(name string) (uint64, error) {
+	for _, qs := range m.sinks {
+		if qs.name != name {
+			continue
+		}
+		qs.mu.Lock()
+		defer qs.mu.Unlock()
+		return qs.dropped, nil
+	}
+	return 0, fmt.Errorf("no such sink: %s", name)
+}
+
+// Close closes the primary store and every sink, draining their queues first. It is safe to call
+// concurrently with Save/SaveCtx: a sink is marked closed under the same lock SaveCtx sends
+// under, so no event can be sent to a sink's queue after (or while) it is closed.
+func (m *MultiStore) Close() error {
+	for _, qs := range m.sinks {
+		qs.mu.Lock()
+		qs.closed = true
+		close(qs.queue)
+		qs.mu.Unlock()
+	}
+	m.wg.Wait()
+
+	var firstErr error
+	for _, qs := range m.sinks {
+		if err := qs.sink.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if err := m.primary.Close(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+
+	return firstErr
+}
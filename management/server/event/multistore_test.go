@@ -0,0 +1,67 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeStore is a minimal in-memory Store used to exercise MultiStore without any real sink.
+type fakeStore struct {
+	mu    sync.Mutex
+	saved []Event
+}
+
+func (f *fakeStore) Save(e Event) (*Event, error) {
+	return f.SaveCtx(context.Background(), e)
+}
+
+func (f *fakeStore) SaveCtx(_ context.Context, e Event) (*Event, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.saved = append(f.saved, e)
+	return &e, nil
+}
+
+func (f *fakeStore) Get(_ string, _, _ int, _ bool) ([]Event, error) {
+	return nil, nil
+}
+
+func (f *fakeStore) Close() error {
+	return nil
+}
+
+// TestMultiStore_ConcurrentSaveClose guards against sending to a sink's queue after Close has
+// closed it, which would panic the process. Run with -race to also catch data races on the
+// closed/dropped bookkeeping.
+func TestMultiStore_ConcurrentSaveClose(t *testing.T) {
+	primary := &fakeStore{}
+	sink := &fakeStore{}
+	m := NewMultiStore(primary, map[string]Store{"sink": sink})
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				if _, err := m.SaveCtx(context.Background(), Event{AccountID: "acc"}); err != nil {
+					t.Errorf("SaveCtx returned error: %v", err)
+					return
+				}
+			}
+		}
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	close(stop)
+	wg.Wait()
+}
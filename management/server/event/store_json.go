@@ -0,0 +1,137 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// JSONFileStore is a Store that appends events as JSON-lines to a file, rotating it once it
+// grows past MaxSizeBytes. It does not support querying; Get always returns an error.
+type JSONFileStore struct {
+	// Path is the file events are appended to
+	Path string
+	// MaxSizeBytes is the size at which the file is rotated. Zero disables rotation.
+	MaxSizeBytes int64
+	// MaxBackups is the number of rotated files to keep around
+	MaxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewJSONFileStore creates a JSONFileStore writing to path, rotating at maxSizeBytes and keeping
+// maxBackups rotated files.
+func NewJSONFileStore(path string, maxSizeBytes int64, maxBackups int) (*JSONFileStore, error) {
+	s := &JSONFileStore{
+		Path:         path,
+		MaxSizeBytes: maxSizeBytes,
+		MaxBackups:   maxBackups,
+	}
+
+	if err := s.openFile(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *JSONFileStore) openFile() error {
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("open event log file: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("stat event log file: %w", err)
+	}
+
+	s.file = f
+	s.size = info.Size()
+	return nil
+}
+
+// Save writes event as a single JSON line.
+func (s *JSONFileStore) Save(event Event) (*Event, error) {
+	return s.SaveCtx(context.Background(), event)
+}
+
+// SaveCtx writes event as a single JSON line. ctx is not used for cancellation; it exists so the
+// sink can be composed behind MultiStore alongside context-aware sinks.
+func (s *JSONFileStore) SaveCtx(_ context.Context, event Event) (*Event, error) {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("marshal event: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.MaxSizeBytes > 0 && s.size+int64(len(line)) > s.MaxSizeBytes {
+		if err := s.rotate(); err != nil {
+			log.Errorf("failed to rotate event log %s: %s", s.Path, err)
+		}
+	}
+
+	n, err := s.file.Write(line)
+	if err != nil {
+		return nil, fmt.Errorf("write event: %w", err)
+	}
+	s.size += int64(n)
+
+	return &event, nil
+}
+
+// rotate closes the current file, shifts existing backups (path.N -> path.N+1, dropping anything
+// past MaxBackups) and opens a fresh file at Path. Caller must hold s.mu.
+func (s *JSONFileStore) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("close event log before rotation: %w", err)
+	}
+
+	if s.MaxBackups > 0 {
+		oldest := fmt.Sprintf("%s.%d", s.Path, s.MaxBackups)
+		if err := os.Remove(oldest); err != nil && !os.IsNotExist(err) {
+			log.Errorf("failed to remove oldest event log backup %s: %s", oldest, err)
+		}
+
+		for i := s.MaxBackups - 1; i >= 1; i-- {
+			src := fmt.Sprintf("%s.%d", s.Path, i)
+			dst := fmt.Sprintf("%s.%d", s.Path, i+1)
+			if err := os.Rename(src, dst); err != nil && !os.IsNotExist(err) {
+				log.Errorf("failed to rotate event log backup %s -> %s: %s", src, dst, err)
+			}
+		}
+
+		if err := os.Rename(s.Path, s.Path+".1"); err != nil && !os.IsNotExist(err) {
+			log.Errorf("failed to rotate event log %s: %s", s.Path, err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return fmt.Errorf("create event log directory: %w", err)
+	}
+
+	return s.openFile()
+}
+
+// Get is not supported by JSONFileStore; events are streamed to disk, not queried back.
+func (s *JSONFileStore) Get(_ string, _, _ int, _ bool) ([]Event, error) {
+	return nil, fmt.Errorf("JSONFileStore does not support querying events")
+}
+
+// Close flushes and closes the underlying file.
+func (s *JSONFileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,70 @@
+//go:build !windows
+
+package event
+
+import (
+	"context"
+	"fmt"
+	"log/syslog"
+)
+
+// SyslogStore forwards events to a local or remote syslog daemon using RFC5424-style severities.
+// It does not support querying; Get always returns an error.
+type SyslogStore struct {
+	Tag string
+
+	writer *syslog.Writer
+}
+
+// NewSyslogStore dials network/raddr (e.g. "udp", "syslog.example.com:514") and returns a
+// SyslogStore tagging every message with tag. A zero-value network dials the local syslog daemon.
+func NewSyslogStore(network, raddr, tag string) (*SyslogStore, error) {
+	w, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, fmt.Errorf("dial syslog: %w", err)
+	}
+
+	return &SyslogStore{
+		Tag:    tag,
+		writer: w,
+	}, nil
+}
+
+// Save writes event to the syslog daemon.
+func (s *SyslogStore) Save(event Event) (*Event, error) {
+	return s.SaveCtx(context.Background(), event)
+}
+
+// SaveCtx writes event to the syslog daemon at a severity derived from event.Severity. ctx is not
+// used for cancellation; it exists so the sink can be composed behind MultiStore alongside
+// context-aware sinks.
+func (s *SyslogStore) SaveCtx(_ context.Context, event Event) (*Event, error) {
+	msg := fmt.Sprintf("account=%s operation=%s modifier=%s target=%s", event.AccountID, event.Operation, event.ModifierID, event.TargetID)
+
+	var err error
+	switch event.Severity {
+	case SeverityCritical:
+		err = s.writer.Crit(msg)
+	case SeverityError:
+		err = s.writer.Err(msg)
+	case SeverityWarn:
+		err = s.writer.Warning(msg)
+	default:
+		err = s.writer.Info(msg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("write syslog message: %w", err)
+	}
+
+	return &event, nil
+}
+
+// Get is not supported by SyslogStore; syslog is a one-way forwarder.
+func (s *SyslogStore) Get(_ string, _, _ int, _ bool) ([]Event, error) {
+	return nil, fmt.Errorf("SyslogStore does not support querying events")
+}
+
+// Close closes the connection to the syslog daemon.
+func (s *SyslogStore) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,182 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultWebhookBatchSize      = 50
+	defaultWebhookFlushInterval  = 5 * time.Second
+	defaultWebhookMaxRetries     = 3
+	defaultWebhookRetryBackoff   = 2 * time.Second
+	defaultWebhookDeliverTimeout = 30 * time.Second
+)
+
+// WebhookStore batches events and POSTs them as a JSON array to a remote URL, retrying transient
+// failures with a fixed backoff. It does not support querying; Get always returns an error.
+type WebhookStore struct {
+	URL            string
+	BatchSize      int
+	FlushInterval  time.Duration
+	MaxRetries     int
+	RetryBackoff   time.Duration
+	DeliverTimeout time.Duration
+
+	client *http.Client
+
+	mu      sync.Mutex
+	pending []Event
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+	doneCh    chan struct{}
+}
+
+// NewWebhookStore creates a WebhookStore that flushes batched events to url every flushInterval,
+// or immediately once batchSize events have accumulated.
+func NewWebhookStore(url string, batchSize int, flushInterval time.Duration) *WebhookStore {
+	if batchSize <= 0 {
+		batchSize = defaultWebhookBatchSize
+	}
+	if flushInterval <= 0 {
+		flushInterval = defaultWebhookFlushInterval
+	}
+
+	s := &WebhookStore{
+		URL:            url,
+		BatchSize:      batchSize,
+		FlushInterval:  flushInterval,
+		MaxRetries:     defaultWebhookMaxRetries,
+		RetryBackoff:   defaultWebhookRetryBackoff,
+		DeliverTimeout: defaultWebhookDeliverTimeout,
+		client:         &http.Client{Timeout: 10 * time.Second},
+		closeCh:        make(chan struct{}),
+		doneCh:         make(chan struct{}),
+	}
+
+	go s.flushLoop()
+
+	return s
+}
+
+// Save queues event for delivery, flushing immediately once the batch is full.
+func (s *WebhookStore) Save(event Event) (*Event, error) {
+	return s.SaveCtx(context.Background(), event)
+}
+
+// SaveCtx queues event for delivery, flushing immediately once the batch is full. ctx is ignored;
+// it has no bearing on the resulting delivery, since a batch also carries
+// events queued by other, unrelated callers (and, via MultiStore, by its own background drain
+// goroutine) — a delivery bounded by one caller's ctx would silently drop everyone else's events
+// if that caller's ctx were canceled mid-retry. Delivery instead runs on an internal timeout; see
+// flush.
+func (s *WebhookStore) SaveCtx(_ context.Context, event Event) (*Event, error) {
+	s.mu.Lock()
+	s.pending = append(s.pending, event)
+	full := len(s.pending) >= s.BatchSize
+	s.mu.Unlock()
+
+	if full {
+		s.flush()
+	}
+
+	return &event, nil
+}
+
+func (s *WebhookStore) flushLoop() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-s.closeCh:
+			s.flush()
+			return
+		}
+	}
+}
+
+// flush delivers the pending batch under a context scoped to the delivery itself, not to whichever
+// caller happened to trigger it, so one caller's canceled/timed-out ctx can't abort delivery of
+// other callers' events.
+func (s *WebhookStore) flush() {
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.mu.Unlock()
+		return
+	}
+	batch := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.DeliverTimeout)
+	defer cancel()
+
+	if err := s.deliver(ctx, batch); err != nil {
+		log.Errorf("failed to deliver %d events to webhook %s: %s", len(batch), s.URL, err)
+	}
+}
+
+func (s *WebhookStore) deliver(ctx context.Context, batch []Event) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("marshal event batch: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= s.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(s.RetryBackoff):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("build webhook request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return lastErr
+}
+
+// Get is not supported by WebhookStore; events are forwarded, not queried back.
+func (s *WebhookStore) Get(_ string, _, _ int, _ bool) ([]Event, error) {
+	return nil, fmt.Errorf("WebhookStore does not support querying events")
+}
+
+// Close stops the flush loop, flushing any pending events first.
+func (s *WebhookStore) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closeCh)
+	})
+	<-s.doneCh
+	return nil
+}
@@ -3,10 +3,10 @@ package client
 import (
 	"context"
 	"fmt"
-	"github.com/netbirdio/netbird/relay/client/dialer/udp"
 	"io"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -28,8 +28,8 @@ type Msg struct {
 }
 
 type connContainer struct {
-	conn     *Conn
-	messages chan Msg
+	conn   *Conn
+	buffer *ringBuffer
 }
 
 type Client struct {
@@ -43,88 +43,162 @@ type Client struct {
 	conns                  map[string]*connContainer
 	connsMutext            sync.Mutex // protect conns and relayConnIsEstablished bool
 
-	relayConn        net.Conn
-	serviceIsRunning bool
-	wgRelayConn      sync.WaitGroup
-	mu               sync.Mutex
-	onDisconnected   chan struct{}
+	relayConn    net.Conn
+	mu           sync.Mutex
+	readLoopDone chan struct{} // closed when the running readLoop exits
+
+	establishedMu sync.Mutex
+	establishedCh chan struct{} // closed when relayConnIsEstablished becomes true, replaced on disconnect
+
+	reconnectAttempt uint64
+	sessionCounter   uint64
+	handshakeRTT     int64 // atomic, nanoseconds; last successful handshake round-trip time
+
+	peerBufferCapacity int
+	dropPolicy         DropPolicy
+	dropThreshold      int
+
+	healthCheckCh chan struct{} // signaled by readLoop when a health check response arrives
 }
 
 func NewClient(ctx context.Context, serverAddress, peerID string) *Client {
 	ctx, ctxCancel := context.WithCancel(ctx)
 	hashedID, hashedStringId := messages.HashID(peerID)
 	return &Client{
-		log:            log.WithField("client_id", hashedStringId),
-		ctx:            ctx,
-		ctxCancel:      ctxCancel,
-		serverAddress:  serverAddress,
-		hashedID:       hashedID,
-		conns:          make(map[string]*connContainer),
-		onDisconnected: make(chan struct{}),
+		log: log.WithFields(log.Fields{
+			"client_id":   hashedStringId,
+			"server_addr": serverAddress,
+		}),
+		ctx:                ctx,
+		ctxCancel:          ctxCancel,
+		serverAddress:      serverAddress,
+		hashedID:           hashedID,
+		conns:              make(map[string]*connContainer),
+		establishedCh:      make(chan struct{}),
+		peerBufferCapacity: defaultPeerBufferCapacity,
+		dropPolicy:         DropOldest,
+		healthCheckCh:      make(chan struct{}, 1),
 	}
 }
 
-func (c *Client) Connect() error {
+// SetDropPolicy configures how a peer's delivery buffer behaves once it's full: capacity is the
+// number of messages buffered per peer (a value <= 0 keeps the current capacity), and threshold
+// is only used by DisconnectAfterThreshold: the peer is disconnected on its threshold-th
+// consecutive drop, and a value <= 0 never disconnects. It only affects connections opened after
+// the call.
+func (c *Client) SetDropPolicy(policy DropPolicy, capacity, threshold int) {
 	c.mu.Lock()
-	if c.serviceIsRunning {
+	defer c.mu.Unlock()
+
+	if capacity > 0 {
+		c.peerBufferCapacity = capacity
+	}
+	c.dropPolicy = policy
+	c.dropThreshold = threshold
+}
+
+// Run connects to the relay server and blocks until ctx (or the Client's own context, cancelled
+// by Close) is done, reconnecting in the background for as long as it runs. It is the canonical
+// blocking entry point; Connect is available for callers that want to manage the lifetime
+// themselves.
+func (c *Client) Run(ctx context.Context) error {
+	if err := c.Connect(ctx); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-c.ctx.Done():
+	}
+
+	return c.close()
+}
+
+// Connect dials the relay server and performs the handshake, honoring ctx cancellation/deadline
+// for both. On success it starts the background read loop and reconnect guard, which keep
+// running for the lifetime of the Client (bounded by ctx and by Close), and returns immediately.
+func (c *Client) Connect(ctx context.Context) error {
+	c.mu.Lock()
+	if c.readLoopDone != nil {
 		c.mu.Unlock()
 		return nil
 	}
 
-	err := c.connect()
+	err := c.connect(ctx)
 	if err != nil {
 		c.mu.Unlock()
 		return err
 	}
 
-	c.serviceIsRunning = true
-
-	c.wgRelayConn.Add(1)
-	go c.readLoop()
+	c.readLoopDone = make(chan struct{})
+	go c.readLoop(c.readLoopDone)
 
 	c.mu.Unlock()
 
-	go func() {
-		<-c.ctx.Done()
-		cErr := c.close()
-		if cErr != nil {
-			log.Errorf("failed to close relay connection: %s", cErr)
-		}
-	}()
-
-	go c.reconnectGuard()
+	go c.reconnectGuard(ctx)
 
 	return nil
 }
 
-func (c *Client) reconnectGuard() {
+func (c *Client) reconnectGuard(ctx context.Context) {
 	for {
-		c.wgRelayConn.Wait()
-
 		c.mu.Lock()
-		if !c.serviceIsRunning {
-			c.mu.Unlock()
+		done := c.readLoopDone
+		c.mu.Unlock()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return
+		case <-c.ctx.Done():
 			return
 		}
 
-		log.Infof("reconnecting to relay server")
-		err := c.connect()
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.ctx.Done():
+			return
+		default:
+		}
+
+		c.mu.Lock()
+
+		attempt := atomic.AddUint64(&c.reconnectAttempt, 1)
+		l := c.log.WithField("attempt", attempt)
+
+		l.Info("reconnecting to relay server")
+		err := c.connect(ctx)
 		if err != nil {
-			log.Errorf("failed to reconnect to relay server: %s", err)
+			l.Errorf("failed to reconnect to relay server: %s", err)
 			c.mu.Unlock()
 			time.Sleep(reconnectingTimeout)
 			continue
 		}
-		log.Infof("reconnected to relay server")
-		c.wgRelayConn.Add(1)
-		go c.readLoop()
+		l.Info("reconnected to relay server")
+		c.readLoopDone = make(chan struct{})
+		go c.readLoop(c.readLoopDone)
 
 		c.mu.Unlock()
-
 	}
 }
 
-func (c *Client) OpenConn(dstPeerID string) (net.Conn, error) {
+// OpenConn returns a net.Conn-like object that reads/writes transport messages addressed to
+// dstPeerID over the relay connection. If the relay connection is not yet established it waits
+// until it is, or until ctx is done.
+func (c *Client) OpenConn(ctx context.Context, dstPeerID string) (net.Conn, error) {
+	c.establishedMu.Lock()
+	establishedCh := c.establishedCh
+	c.establishedMu.Unlock()
+
+	select {
+	case <-establishedCh:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.ctx.Done():
+		return nil, fmt.Errorf("relay client is closed")
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -135,18 +209,14 @@ func (c *Client) OpenConn(dstPeerID string) (net.Conn, error) {
 		return nil, fmt.Errorf("relay connection is not established")
 	}
 
-	if !c.serviceIsRunning {
-		return nil, fmt.Errorf("relay connection is not established")
-	}
-
 	hashedID, hashedStringID := messages.HashID(dstPeerID)
-	log.Infof("open connection to peer: %s", hashedStringID)
-	messageBuffer := make(chan Msg, 2)
-	conn := NewConn(c, hashedID, hashedStringID, c.generateConnReaderFN(messageBuffer))
+	c.log.WithField("dst_peer", hashedStringID).Info("open connection")
+	buffer := newRingBuffer(c.peerBufferCapacity, c.dropPolicy, c.dropThreshold)
+	conn := NewConn(c, hashedID, hashedStringID, c.generateConnReaderFN(buffer), buffer)
 
 	c.conns[hashedStringID] = &connContainer{
 		conn,
-		messageBuffer,
+		buffer,
 	}
 	return conn, nil
 }
@@ -156,24 +226,111 @@ func (c *Client) Close() error {
 	return c.close()
 }
 
-func (c *Client) connect() error {
-	conn, err := udp.Dial(c.serverAddress)
+// Connected reports whether the relay connection is currently established.
+func (c *Client) Connected() bool {
+	c.connsMutext.Lock()
+	defer c.connsMutext.Unlock()
+	return c.relayConnIsEstablished
+}
+
+// RTT returns the round-trip time of the last successful handshake or health check probe,
+// usable as a latency estimate for this relay. It is zero until the first successful connect.
+func (c *Client) RTT() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.handshakeRTT))
+}
+
+// Probe sends a health check to the relay server over the existing connection and waits for the
+// response, updating RTT on success. It returns an error if the connection is not established or
+// ctx is done before a response arrives.
+func (c *Client) Probe(ctx context.Context) (time.Duration, error) {
+	c.mu.Lock()
+	if c.readLoopDone == nil {
+		c.mu.Unlock()
+		return 0, fmt.Errorf("relay connection is not established")
+	}
+	conn := c.relayConn
+	c.mu.Unlock()
+
+	if !c.Connected() {
+		return 0, fmt.Errorf("relay connection is not established")
+	}
+
+	msg, err := messages.MarshalHealthCheck()
+	if err != nil {
+		return 0, fmt.Errorf("marshal health check: %w", err)
+	}
+
+	start := time.Now()
+	if _, err := conn.Write(msg); err != nil {
+		return 0, fmt.Errorf("write health check: %w", err)
+	}
+
+	select {
+	case <-c.healthCheckCh:
+		rtt := time.Since(start)
+		atomic.StoreInt64(&c.handshakeRTT, int64(rtt))
+		return rtt, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// mergeContext returns a context that is done when either a or b is done, so an operation bound
+// to a caller-supplied ctx (a) can still be interrupted by a second, independent lifetime (b).
+// Callers must always invoke the returned cancel to release the goroutine that watches b.
+func mergeContext(a, b context.Context) (context.Context, context.CancelFunc) {
+	merged, cancel := context.WithCancel(a)
+
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-b.Done():
+			cancel()
+		case <-stop:
+		}
+	}()
+
+	return merged, func() {
+		close(stop)
+		cancel()
+	}
+}
+
+// connect dials the relay server and performs the handshake, bounded by ctx and by c.ctx, so a
+// Close() that fires while a (re)connect attempt is in flight interrupts it promptly instead of
+// waiting out the external ctx, which callers like reconnectGuard may have set to something
+// long-lived.
+func (c *Client) connect(ctx context.Context) error {
+	ctx, cancel := mergeContext(ctx, c.ctx)
+	defer cancel()
+
+	sessionID := atomic.AddUint64(&c.sessionCounter, 1)
+	l := c.log.WithField("session_id", sessionID)
+
+	conn, err := dialAddress(ctx, c.serverAddress)
 	if err != nil {
 		return err
 	}
 	c.relayConn = conn
 
-	err = c.handShake()
+	hsStart := time.Now()
+	err = c.handShake(ctx, l)
 	if err != nil {
 		cErr := conn.Close()
 		if cErr != nil {
-			log.Errorf("failed to close connection: %s", cErr)
+			l.Errorf("failed to close connection: %s", cErr)
 		}
 		c.relayConn = nil
 		return err
 	}
+	atomic.StoreInt64(&c.handshakeRTT, int64(time.Since(hsStart)))
 
 	c.relayConnIsEstablished = true
+
+	c.establishedMu.Lock()
+	close(c.establishedCh)
+	c.establishedMu.Unlock()
+
 	return nil
 }
 
@@ -181,74 +338,83 @@ func (c *Client) close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if !c.serviceIsRunning {
+	if c.readLoopDone == nil {
 		return nil
 	}
 
-	c.serviceIsRunning = false
+	done := c.readLoopDone
+	c.readLoopDone = nil
 
 	err := c.relayConn.Close()
 
-	c.wgRelayConn.Wait()
+	<-done
 
 	return err
 }
 
-func (c *Client) handShake() error {
+func (c *Client) handShake(ctx context.Context, l *log.Entry) error {
+	unblock := make(chan struct{})
+	defer close(unblock)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = c.relayConn.Close()
+		case <-unblock:
+		}
+	}()
+
 	defer func() {
 		err := c.relayConn.SetReadDeadline(time.Time{})
 		if err != nil {
-			log.Errorf("failed to reset read deadline: %s", err)
+			l.Errorf("failed to reset read deadline: %s", err)
 		}
 	}()
 
 	msg, err := messages.MarshalHelloMsg(c.hashedID)
 	if err != nil {
-		log.Errorf("failed to marshal hello message: %s", err)
+		l.Errorf("failed to marshal hello message: %s", err)
 		return err
 	}
 	_, err = c.relayConn.Write(msg)
 	if err != nil {
-		log.Errorf("failed to send hello message: %s", err)
+		l.Errorf("failed to send hello message: %s", err)
 		return err
 	}
 
 	err = c.relayConn.SetReadDeadline(time.Now().Add(serverResponseTimeout))
 	if err != nil {
-		log.Errorf("failed to set read deadline: %s", err)
+		l.Errorf("failed to set read deadline: %s", err)
 		return err
 	}
 
 	buf := make([]byte, 1500) // todo: optimise buffer size
 	n, err := c.relayConn.Read(buf)
 	if err != nil {
-		log.Errorf("failed to read hello response: %s", err)
+		l.Errorf("failed to read hello response: %s", err)
 		return err
 	}
 
 	msgType, err := messages.DetermineServerMsgType(buf[:n])
 	if err != nil {
-		log.Errorf("failed to determine message type: %s", err)
+		l.Errorf("failed to determine message type: %s", err)
 		return err
 	}
 
 	if msgType != messages.MsgTypeHelloResponse {
-		log.Errorf("unexpected message type: %s", msgType)
+		l.Errorf("unexpected message type: %s", msgType)
 		return fmt.Errorf("unexpected message type")
 	}
 	return nil
 }
 
-func (c *Client) readLoop() {
+func (c *Client) readLoop(done chan struct{}) {
 	var errExit error
 	var n int
 	for {
 		buf := make([]byte, bufferSize)
 		n, errExit = c.relayConn.Read(buf)
 		if errExit != nil {
-			if c.serviceIsRunning {
-				c.log.Debugf("failed to read message from relay server: %s", errExit)
-			}
+			c.log.Debugf("failed to read message from relay server: %s", errExit)
 			break
 		}
 
@@ -269,30 +435,41 @@ func (c *Client) readLoop() {
 
 			container, ok := c.conns[stringID]
 			if !ok {
-				c.log.Errorf("peer not found: %s", stringID)
+				c.log.WithField("remote_peer", stringID).Errorf("peer not found")
 				continue
 			}
 
-			container.messages <- Msg{
-				buf[:n],
+			c.log.WithField("remote_peer", stringID).Trace("received transport message")
+			if disconnect := container.buffer.push(Msg{buf[:n]}); disconnect {
+				c.log.WithField("remote_peer", stringID).Warnf("peer exceeded consecutive drop threshold, disconnecting")
+				if err := c.closeConn(stringID); err != nil {
+					c.log.WithField("remote_peer", stringID).Errorf("failed to close connection: %s", err)
+				}
+			}
+		case messages.MsgTypeHealthCheckResponse:
+			select {
+			case c.healthCheckCh <- struct{}{}:
+			default: // a response signal is already pending; drop this one
 			}
 		}
 	}
 
-	if c.serviceIsRunning {
-		_ = c.relayConn.Close()
-	}
+	_ = c.relayConn.Close()
 
 	c.connsMutext.Lock()
 	c.relayConnIsEstablished = false
 	for _, container := range c.conns {
-		close(container.messages)
+		container.buffer.close()
 	}
 	c.conns = make(map[string]*connContainer)
 	c.connsMutext.Unlock()
 
+	c.establishedMu.Lock()
+	c.establishedCh = make(chan struct{})
+	c.establishedMu.Unlock()
+
 	c.log.Tracef("exit from read loop")
-	c.wgRelayConn.Done()
+	close(done)
 }
 
 func (c *Client) writeTo(id string, dstID []byte, payload []byte) (int, error) {
@@ -306,14 +483,14 @@ func (c *Client) writeTo(id string, dstID []byte, payload []byte) (int, error) {
 	msg := messages.MarshalTransportMsg(dstID, payload)
 	n, err := c.relayConn.Write(msg)
 	if err != nil {
-		log.Errorf("failed to write transport message: %s", err)
+		c.log.WithField("remote_peer", id).Errorf("failed to write transport message: %s", err)
 	}
 	return n, err
 }
 
-func (c *Client) generateConnReaderFN(msgChannel chan Msg) func(b []byte) (n int, err error) {
+func (c *Client) generateConnReaderFN(buffer *ringBuffer) func(b []byte) (n int, err error) {
 	return func(b []byte) (n int, err error) {
-		msg, ok := <-msgChannel
+		msg, ok := buffer.pop()
 		if !ok {
 			return 0, io.EOF
 		}
@@ -335,11 +512,11 @@ func (c *Client) closeConn(id string) error {
 	c.connsMutext.Lock()
 	defer c.connsMutext.Unlock()
 
-	conn, ok := c.conns[id]
+	container, ok := c.conns[id]
 	if !ok {
 		return fmt.Errorf("connection already closed")
 	}
-	close(conn.messages)
+	container.buffer.close()
 	delete(c.conns, id)
 
 	return nil
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"net"
+	"time"
+)
+
+// Conn implements net.Conn over a single peer channel multiplexed on top of a Client's relay
+// connection.
+type Conn struct {
+	client      *Client
+	dstID       []byte
+	dstStringID string
+	readFn      func(b []byte) (int, error)
+	buffer      *ringBuffer
+}
+
+// NewConn wraps a single peer channel as a net.Conn. readFn pulls the next payload delivered by
+// the Client's read loop; buffer backs readFn and is kept here only so Stats can report on it.
+func NewConn(client *Client, dstID []byte, dstStringID string, readFn func(b []byte) (int, error), buffer *ringBuffer) *Conn {
+	return &Conn{
+		client:      client,
+		dstID:       dstID,
+		dstStringID: dstStringID,
+		readFn:      readFn,
+		buffer:      buffer,
+	}
+}
+
+func (c *Conn) Read(b []byte) (int, error) {
+	return c.readFn(b)
+}
+
+func (c *Conn) Write(b []byte) (int, error) {
+	return c.client.writeTo(c.dstStringID, c.dstID, b)
+}
+
+func (c *Conn) Close() error {
+	return c.client.closeConn(c.dstStringID)
+}
+
+func (c *Conn) LocalAddr() net.Addr {
+	return relayAddr{value: "relay-client"}
+}
+
+func (c *Conn) RemoteAddr() net.Addr {
+	return relayAddr{value: c.dstStringID}
+}
+
+func (c *Conn) SetDeadline(_ time.Time) error { return nil }
+
+func (c *Conn) SetReadDeadline(_ time.Time) error { return nil }
+
+func (c *Conn) SetWriteDeadline(_ time.Time) error { return nil }
+
+// ConnStats reports delivery health for a single peer channel.
+type ConnStats struct {
+	// Dropped is the number of inbound messages discarded because this peer's delivery buffer
+	// was full.
+	Dropped uint64
+}
+
+// Stats returns the current drop counters for this peer channel.
+func (c *Conn) Stats() ConnStats {
+	return ConnStats{Dropped: c.buffer.droppedCount()}
+}
+
+// relayAddr is a minimal net.Addr for peer channels, which have no real network address of
+// their own.
+type relayAddr struct {
+	value string
+}
+
+func (relayAddr) Network() string { return "relay" }
+
+func (a relayAddr) String() string { return a.value }
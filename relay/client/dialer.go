@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/netbirdio/netbird/relay/client/dialer/quic"
+	"github.com/netbirdio/netbird/relay/client/dialer/tcp"
+	"github.com/netbirdio/netbird/relay/client/dialer/tls"
+	"github.com/netbirdio/netbird/relay/client/dialer/udp"
+	"github.com/netbirdio/netbird/relay/client/dialer/ws"
+)
+
+// Dialer opens a transport connection to a relay server address. Implementations are registered
+// against a URL scheme (udp://, tcp://, tls://, quic://, wss://) via RegisterDialer so the server
+// address alone selects the transport.
+type Dialer interface {
+	Dial(ctx context.Context, addr string) (net.Conn, error)
+}
+
+var (
+	dialersMu sync.RWMutex
+	dialers   = map[string]Dialer{}
+)
+
+func init() {
+	RegisterDialer("udp", udpDialer{})
+	RegisterDialer("tcp", tcp.Dialer{})
+	RegisterDialer("tls", tls.NewDialer())
+	RegisterDialer("quic", quic.Dialer{})
+	RegisterDialer("wss", ws.Dialer{})
+}
+
+// RegisterDialer makes d available for server addresses using the given URL scheme (without the
+// "://" separator, e.g. "tcp"). It is meant to be called from init(), typically once per scheme;
+// registering the same scheme twice overwrites the previous Dialer.
+func RegisterDialer(scheme string, d Dialer) {
+	dialersMu.Lock()
+	defer dialersMu.Unlock()
+	dialers[scheme] = d
+}
+
+// dialAddress dials a relay server address of the form "scheme://host:port". Addresses without a
+// "scheme://" prefix are treated as "udp://" for backwards compatibility with plain host:port
+// addresses.
+func dialAddress(ctx context.Context, address string) (net.Conn, error) {
+	scheme, hostport := splitSchemeAddr(address)
+
+	dialersMu.RLock()
+	d, ok := dialers[scheme]
+	dialersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no dialer registered for scheme %q", scheme)
+	}
+
+	return d.Dial(ctx, hostport)
+}
+
+func splitSchemeAddr(address string) (scheme, hostport string) {
+	if i := strings.Index(address, "://"); i >= 0 {
+		return address[:i], address[i+3:]
+	}
+	return "udp", address
+}
+
+// udpDialer adapts the existing udp.Dial, which has no context support of its own, so the dial
+// still aborts if ctx is done first.
+type udpDialer struct{}
+
+func (udpDialer) Dial(ctx context.Context, addr string) (net.Conn, error) {
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+
+	resCh := make(chan result, 1)
+	go func() {
+		conn, err := udp.Dial(addr)
+		resCh <- result{conn, err}
+	}()
+
+	select {
+	case res := <-resCh:
+		return res.conn, res.err
+	case <-ctx.Done():
+		go func() {
+			res := <-resCh
+			if res.conn != nil {
+				_ = res.conn.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,51 @@
+// Package quic implements a client.Dialer for relay connections carried over a QUIC stream.
+package quic
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	"github.com/quic-go/quic-go"
+)
+
+const nextProto = "netbird-relay"
+
+// Dialer dials a relay server over QUIC, opening a single bidirectional stream per Dial call.
+type Dialer struct {
+	// TLSConfig overrides the TLS config used for the QUIC handshake; if nil, a default config
+	// advertising the netbird-relay ALPN is used.
+	TLSConfig *tls.Config
+}
+
+// Dial establishes a QUIC connection to addr and opens a stream on it, honoring ctx
+// cancellation/deadline for both steps.
+func (d Dialer) Dial(ctx context.Context, addr string) (net.Conn, error) {
+	cfg := d.TLSConfig
+	if cfg == nil {
+		cfg = &tls.Config{NextProtos: []string{nextProto}}
+	}
+
+	conn, err := quic.DialAddr(ctx, addr, cfg, nil)
+	if err != nil {
+		return nil, fmt.Errorf("quic dial: %w", err)
+	}
+
+	stream, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("quic open stream: %w", err)
+	}
+
+	return &streamConn{Stream: stream, conn: conn}, nil
+}
+
+// streamConn adapts a quic.Stream (which has no notion of local/remote network address) to
+// net.Conn by pulling those from the parent quic.Connection.
+type streamConn struct {
+	quic.Stream
+	conn quic.Connection
+}
+
+func (s *streamConn) LocalAddr() net.Addr  { return s.conn.LocalAddr() }
+func (s *streamConn) RemoteAddr() net.Addr { return s.conn.RemoteAddr() }
@@ -0,0 +1,16 @@
+// Package tcp implements a client.Dialer for plain TCP relay connections.
+package tcp
+
+import (
+	"context"
+	"net"
+)
+
+// Dialer dials a relay server over plain TCP.
+type Dialer struct{}
+
+// Dial opens a TCP connection to addr, honoring ctx cancellation/deadline.
+func (Dialer) Dial(ctx context.Context, addr string) (net.Conn, error) {
+	var d net.Dialer
+	return d.DialContext(ctx, "tcp", addr)
+}
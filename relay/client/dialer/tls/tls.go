@@ -0,0 +1,64 @@
+// Package tls implements a client.Dialer for relay connections secured with TLS, optionally
+// pinning the expected server certificate instead of relying on the system trust store.
+package tls
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net"
+)
+
+// Dialer dials a relay server over TLS.
+type Dialer struct {
+	// ServerName overrides the SNI sent during the handshake; if empty, the host portion of the
+	// dialed address is used.
+	ServerName string
+	// PinnedSHA256 optionally pins the expected leaf certificate by the hex-encoded SHA-256 of
+	// its DER bytes. When set, normal chain/hostname verification is skipped in favor of the pin.
+	PinnedSHA256 string
+}
+
+// NewDialer returns a Dialer that verifies server certificates normally; set ServerName or
+// PinnedSHA256 on the result to customize verification.
+func NewDialer() *Dialer {
+	return &Dialer{}
+}
+
+// Dial opens a TLS connection to addr, honoring ctx cancellation/deadline.
+func (d *Dialer) Dial(ctx context.Context, addr string) (net.Conn, error) {
+	serverName := d.ServerName
+	if serverName == "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+		serverName = host
+	}
+
+	cfg := &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: d.PinnedSHA256 != "", //nolint:gosec // verification is replaced by the pin check below
+	}
+	if d.PinnedSHA256 != "" {
+		cfg.VerifyPeerCertificate = d.verifyPinned
+	}
+
+	dialer := &tls.Dialer{Config: cfg}
+	return dialer.DialContext(ctx, "tcp", addr)
+}
+
+func (d *Dialer) verifyPinned(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("tls: no certificate presented to verify pin")
+	}
+
+	sum := sha256.Sum256(rawCerts[0])
+	if hex.EncodeToString(sum[:]) != d.PinnedSHA256 {
+		return fmt.Errorf("tls: leaf certificate does not match pinned SHA-256")
+	}
+	return nil
+}
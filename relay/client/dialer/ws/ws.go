@@ -0,0 +1,24 @@
+// Package ws implements a client.Dialer for relay connections tunneled over a WebSocket, letting
+// a client reach a relay server through HTTP-only egress.
+package ws
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"nhooyr.io/websocket"
+)
+
+// Dialer dials a relay server over a secure WebSocket (wss://).
+type Dialer struct{}
+
+// Dial opens a WebSocket connection to addr and wraps it as a net.Conn carrying binary frames.
+func (Dialer) Dial(ctx context.Context, addr string) (net.Conn, error) {
+	conn, _, err := websocket.Dial(ctx, "wss://"+addr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("websocket dial: %w", err)
+	}
+
+	return websocket.NetConn(ctx, conn, websocket.MessageBinary), nil
+}
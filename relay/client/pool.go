@@ -0,0 +1,301 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultHealthCheckInterval = 30 * time.Second
+	defaultProbeTimeout        = 5 * time.Second
+)
+
+// RelayStats reports observability data for a single relay server within a Pool.
+type RelayStats struct {
+	Address          string
+	Connected        bool
+	RTT              time.Duration
+	ConnectAttempts  uint64
+	ConnectSuccesses uint64
+	PeerCount        int
+}
+
+// poolMember tracks a single relay's Client plus the bookkeeping a Pool needs to health check it
+// and migrate its peers elsewhere.
+type poolMember struct {
+	client *Client
+
+	mu               sync.Mutex
+	connectAttempts  uint64
+	connectSuccesses uint64
+	peers            map[string]*poolConn // dstPeerID -> the conn currently routed through this relay
+}
+
+// Pool keeps warm connections to a set of relay servers, health-probes them periodically with a
+// real health-check round trip (not just the last handshake time), and routes OpenConn calls to the
+// lowest-latency healthy one. When a relay fails a probe, peer conns that were using it are
+// transparently migrated onto the next best relay: the net.Conn a caller already holds keeps
+// working, it just starts reading/writing through the new relay underneath.
+type Pool struct {
+	ctx                 context.Context
+	cancel              context.CancelFunc
+	peerID              string
+	healthCheckInterval time.Duration
+	probeTimeout        time.Duration
+
+	mu      sync.RWMutex
+	members map[string]*poolMember // keyed by relay server address
+}
+
+// NewPool creates a Pool that keeps connections open to every address in addresses, health
+// checking them every healthCheckInterval (a value <= 0 defaults to 30s).
+func NewPool(ctx context.Context, addresses []string, peerID string, healthCheckInterval time.Duration) *Pool {
+	if healthCheckInterval <= 0 {
+		healthCheckInterval = defaultHealthCheckInterval
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	p := &Pool{
+		ctx:                 ctx,
+		cancel:              cancel,
+		peerID:              peerID,
+		healthCheckInterval: healthCheckInterval,
+		probeTimeout:        defaultProbeTimeout,
+		members:             make(map[string]*poolMember, len(addresses)),
+	}
+
+	for _, addr := range addresses {
+		p.members[addr] = &poolMember{
+			client: NewClient(ctx, addr, peerID),
+			peers:  make(map[string]*poolConn),
+		}
+	}
+
+	return p
+}
+
+// Run connects to every relay in the pool and starts health probing. It blocks until ctx (or the
+// Pool's own context, cancelled by Close) is done.
+func (p *Pool) Run(ctx context.Context) error {
+	p.mu.RLock()
+	members := make([]*poolMember, 0, len(p.members))
+	for _, m := range p.members {
+		members = append(members, m)
+	}
+	p.mu.RUnlock()
+
+	for _, m := range members {
+		m.mu.Lock()
+		m.connectAttempts++
+		m.mu.Unlock()
+
+		if err := m.client.Connect(p.ctx); err != nil {
+			log.Errorf("pool: failed to connect to relay %s: %s", m.client.serverAddress, err)
+			continue
+		}
+
+		m.mu.Lock()
+		m.connectSuccesses++
+		m.mu.Unlock()
+	}
+
+	go p.healthLoop()
+
+	select {
+	case <-ctx.Done():
+	case <-p.ctx.Done():
+	}
+
+	return p.Close()
+}
+
+func (p *Pool) healthLoop() {
+	ticker := time.NewTicker(p.healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.probeAll()
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+// probeAll sends a real health-check round trip to every connected relay and migrates a relay's
+// peers away from it the moment a probe fails or times out, rather than relying on the stale
+// Connected() flag the client's own reconnect loop maintains.
+func (p *Pool) probeAll() {
+	p.mu.RLock()
+	members := make(map[string]*poolMember, len(p.members))
+	for addr, m := range p.members {
+		members[addr] = m
+	}
+	p.mu.RUnlock()
+
+	for addr, m := range members {
+		if !m.client.Connected() {
+			log.Warnf("pool: relay %s unhealthy, migrating its peers", addr)
+			p.migrateAwayFrom(addr, m)
+			continue
+		}
+
+		m.mu.Lock()
+		m.connectAttempts++
+		m.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(p.ctx, p.probeTimeout)
+		_, err := m.client.Probe(ctx)
+		cancel()
+
+		if err != nil {
+			log.Warnf("pool: relay %s failed health probe: %s, migrating its peers", addr, err)
+			p.migrateAwayFrom(addr, m)
+			continue
+		}
+
+		m.mu.Lock()
+		m.connectSuccesses++
+		m.mu.Unlock()
+	}
+}
+
+// migrateAwayFrom moves every peer conn that was routed through m onto the next best healthy
+// relay. Each peer's poolConn keeps the identity the original OpenConn caller is holding: instead
+// of opening a fresh conn and discarding it, migrateAwayFrom swaps the new underlying net.Conn
+// into the existing poolConn, so a caller blocked in Read on the old (now-dead) conn transparently
+// picks up the replacement. It does not yet notify the remote peer of the move via a relay control
+// message — that requires a wire message this snapshot's relay/messages package doesn't define —
+// so the remote side must notice the old relay is gone and re-hash on its own.
+func (p *Pool) migrateAwayFrom(addr string, m *poolMember) {
+	m.mu.Lock()
+	peers := make(map[string]*poolConn, len(m.peers))
+	for peerID, pc := range m.peers {
+		peers[peerID] = pc
+	}
+	m.peers = make(map[string]*poolConn)
+	m.mu.Unlock()
+
+	for peerID, pc := range peers {
+		newAddr, newMember, err := p.best()
+		if err != nil {
+			log.Errorf("pool: failed to migrate peer %s away from relay %s: %s", peerID, addr, err)
+			continue
+		}
+
+		conn, err := newMember.client.OpenConn(p.ctx, peerID)
+		if err != nil {
+			log.Errorf("pool: failed to migrate peer %s away from relay %s: %s", peerID, addr, err)
+			continue
+		}
+
+		pc.swap(conn)
+
+		newMember.mu.Lock()
+		newMember.peers[peerID] = pc
+		newMember.mu.Unlock()
+
+		log.Debugf("pool: migrated peer %s from relay %s to relay %s", peerID, addr, newAddr)
+	}
+}
+
+// best returns the address and member of the healthy relay with the lowest RTT, as last measured
+// by its handshake or health check probe.
+func (p *Pool) best() (string, *poolMember, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var bestAddr string
+	var bestMember *poolMember
+	var bestRTT time.Duration
+
+	for addr, m := range p.members {
+		if !m.client.Connected() {
+			continue
+		}
+
+		rtt := m.client.RTT()
+		if bestMember == nil || rtt < bestRTT {
+			bestAddr, bestMember, bestRTT = addr, m, rtt
+		}
+	}
+
+	if bestMember == nil {
+		return "", nil, fmt.Errorf("no healthy relay available")
+	}
+	return bestAddr, bestMember, nil
+}
+
+// OpenConn opens a connection to dstPeerID over the lowest-latency healthy relay in the pool. The
+// returned net.Conn's identity is stable for the life of the connection: if the relay backing it
+// later goes unhealthy, migrateAwayFrom swaps in a replacement underlying conn transparently, so
+// the caller never has to re-open it.
+func (p *Pool) OpenConn(ctx context.Context, dstPeerID string) (net.Conn, error) {
+	addr, m, err := p.best()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := m.client.OpenConn(ctx, dstPeerID)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := newPoolConn(conn)
+
+	m.mu.Lock()
+	m.peers[dstPeerID] = pc
+	m.mu.Unlock()
+
+	log.Debugf("pool: routed peer %s through relay %s", dstPeerID, addr)
+	return pc, nil
+}
+
+// Stats returns per-relay observability data: RTT, connect attempt/success counts and current
+// peer count, sorted by address. The attempt/success counts cover both the initial connect made by
+// Run and every health probe; reconnects made by a Client on its own are not counted.
+func (p *Pool) Stats() []RelayStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	stats := make([]RelayStats, 0, len(p.members))
+	for addr, m := range p.members {
+		m.mu.Lock()
+		s := RelayStats{
+			Address:          addr,
+			Connected:        m.client.Connected(),
+			RTT:              m.client.RTT(),
+			ConnectAttempts:  m.connectAttempts,
+			ConnectSuccesses: m.connectSuccesses,
+			PeerCount:        len(m.peers),
+		}
+		m.mu.Unlock()
+		stats = append(stats, s)
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Address < stats[j].Address })
+	return stats
+}
+
+// Close closes every relay connection in the pool.
+func (p *Pool) Close() error {
+	p.cancel()
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var firstErr error
+	for _, m := range p.members {
+		if err := m.client.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
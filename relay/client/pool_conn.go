@@ -0,0 +1,139 @@
+package client
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// migrationGraceWait bounds how long a Read that just failed will wait for migrateAwayFrom to
+// swap in a replacement conn before giving up and returning the original error. A read can fail
+// for reasons that have nothing to do with relay health (e.g. ringbuffer.go's
+// DisconnectAfterThreshold policy closing the peer's buffer) and no migration will ever be
+// scheduled for those, so the wait must not be unbounded. Var (not const) so tests can shrink it.
+var migrationGraceWait = 2 * time.Second
+
+// poolConn is the net.Conn a Pool hands back from OpenConn. Its identity is stable for the
+// caller's whole session even though the relay backing it can change: Pool.migrateAwayFrom swaps
+// the underlying conn via swap() when the relay it was using goes unhealthy, and any Read blocked
+// on the old (now-dead) conn wakes up and retries against the new one instead of returning a
+// permanent error.
+type poolConn struct {
+	mu      sync.Mutex
+	current net.Conn
+	closed  bool
+	changed chan struct{} // closed and replaced every time current or closed changes
+}
+
+func newPoolConn(current net.Conn) *poolConn {
+	return &poolConn{current: current, changed: make(chan struct{})}
+}
+
+// swap replaces the underlying conn, waking up any Read waiting on the old one failing.
+// The old conn is closed since OpenConn never hands it out a second time.
+func (pc *poolConn) swap(next net.Conn) {
+	pc.mu.Lock()
+	old := pc.current
+	pc.current = next
+	close(pc.changed)
+	pc.changed = make(chan struct{})
+	pc.mu.Unlock()
+
+	if old != nil {
+		_ = old.Close()
+	}
+}
+
+func (pc *poolConn) Read(b []byte) (int, error) {
+	for {
+		pc.mu.Lock()
+		if pc.closed {
+			pc.mu.Unlock()
+			return 0, net.ErrClosed
+		}
+		cur := pc.current
+		changed := pc.changed
+		pc.mu.Unlock()
+
+		n, err := cur.Read(b)
+		if err == nil {
+			return n, nil
+		}
+
+		// The read failed. Give migrateAwayFrom a bounded window to swap in a replacement before
+		// giving up: if it does, retry against the new conn; otherwise (timeout, or the failure
+		// wasn't relay-health related and no migration is coming) surface the original error.
+		select {
+		case <-changed:
+			pc.mu.Lock()
+			closed := pc.closed
+			migrated := pc.current != cur
+			pc.mu.Unlock()
+			if closed {
+				return 0, net.ErrClosed
+			}
+			if migrated {
+				continue
+			}
+			return n, err
+		case <-time.After(migrationGraceWait):
+			return n, err
+		}
+	}
+}
+
+func (pc *poolConn) Write(b []byte) (int, error) {
+	pc.mu.Lock()
+	cur := pc.current
+	closed := pc.closed
+	pc.mu.Unlock()
+
+	if closed {
+		return 0, net.ErrClosed
+	}
+	return cur.Write(b)
+}
+
+func (pc *poolConn) Close() error {
+	pc.mu.Lock()
+	if pc.closed {
+		pc.mu.Unlock()
+		return nil
+	}
+	pc.closed = true
+	cur := pc.current
+	close(pc.changed)
+	pc.changed = make(chan struct{})
+	pc.mu.Unlock()
+
+	if cur == nil {
+		return nil
+	}
+	return cur.Close()
+}
+
+func (pc *poolConn) LocalAddr() net.Addr {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	return pc.current.LocalAddr()
+}
+
+func (pc *poolConn) RemoteAddr() net.Addr {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	return pc.current.RemoteAddr()
+}
+
+func (pc *poolConn) SetDeadline(t time.Time) error { return pc.currentConn().SetDeadline(t) }
+
+func (pc *poolConn) SetReadDeadline(t time.Time) error { return pc.currentConn().SetReadDeadline(t) }
+
+func (pc *poolConn) SetWriteDeadline(t time.Time) error {
+	return pc.currentConn().SetWriteDeadline(t)
+}
+
+func (pc *poolConn) currentConn() net.Conn {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	return pc.current
+}
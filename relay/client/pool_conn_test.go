@@ -0,0 +1,129 @@
+package client
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+)
+
+// TestPoolConn_SwapMigratesBlockedRead proves that once migrateAwayFrom (via swap) replaces a
+// poolConn's underlying net.Conn, a Read that was blocked on the old (now-dead) conn ends up
+// reading from the new one instead of returning a permanent error to the caller.
+func TestPoolConn_SwapMigratesBlockedRead(t *testing.T) {
+	oldServer, oldClient := net.Pipe()
+	newServer, newClient := net.Pipe()
+
+	pc := newPoolConn(oldClient)
+
+	readResult := make(chan []byte, 1)
+	readErr := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 5)
+		n, err := pc.Read(buf)
+		if err != nil {
+			readErr <- err
+			return
+		}
+		readResult <- buf[:n]
+	}()
+
+	// Kill the old conn as if the relay it belonged to went unhealthy; pc.Read should be blocked
+	// inside oldClient.Read at this point, not returned.
+	_ = oldServer.Close()
+	_ = oldClient.Close()
+
+	// Give the blocked Read a moment to observe the failure before migrating.
+	time.Sleep(10 * time.Millisecond)
+
+	pc.swap(newClient)
+
+	go func() {
+		_, _ = newServer.Write([]byte("hello"))
+	}()
+
+	select {
+	case got := <-readResult:
+		if !bytes.Equal(got, []byte("hello")) {
+			t.Fatalf("got %q, want %q", got, "hello")
+		}
+	case err := <-readErr:
+		t.Fatalf("Read returned error after migration instead of using the new conn: %v", err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for Read to pick up the migrated conn")
+	}
+
+	_ = newServer.Close()
+	_ = pc.Close()
+}
+
+// TestPoolConn_WriteUsesCurrentConn proves a Write after swap goes to the new conn, not the old
+// (closed) one.
+func TestPoolConn_WriteUsesCurrentConn(t *testing.T) {
+	oldServer, oldClient := net.Pipe()
+	newServer, newClient := net.Pipe()
+
+	pc := newPoolConn(oldClient)
+	pc.swap(newClient)
+	_ = oldServer.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := pc.Write([]byte("ping")); err != nil {
+			t.Errorf("Write after swap returned error: %v", err)
+		}
+	}()
+
+	buf := make([]byte, 4)
+	if err := newServer.SetReadDeadline(time.Now().Add(2 * time.Second)); err != nil {
+		t.Fatalf("SetReadDeadline: %v", err)
+	}
+	n, err := newServer.Read(buf)
+	if err != nil {
+		t.Fatalf("reading from new conn: %v", err)
+	}
+	if !bytes.Equal(buf[:n], []byte("ping")) {
+		t.Fatalf("got %q, want %q", buf[:n], "ping")
+	}
+
+	<-done
+	_ = newServer.Close()
+	_ = pc.Close()
+}
+
+// TestPoolConn_ReadReturnsErrorWhenNoMigrationComes proves that a Read failure which isn't
+// followed by a swap (e.g. a peer-level close unrelated to relay health, like
+// DisconnectAfterThreshold in ringbuffer.go) returns the original error within
+// migrationGraceWait instead of blocking forever.
+func TestPoolConn_ReadReturnsErrorWhenNoMigrationComes(t *testing.T) {
+	orig := migrationGraceWait
+	migrationGraceWait = 50 * time.Millisecond
+	defer func() { migrationGraceWait = orig }()
+
+	server, client := net.Pipe()
+	pc := newPoolConn(client)
+
+	readErr := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 5)
+		_, err := pc.Read(buf)
+		readErr <- err
+	}()
+
+	// Close as a peer-level close would, with no relay failure and therefore no migration ever
+	// scheduled for this poolConn.
+	_ = server.Close()
+	_ = client.Close()
+
+	select {
+	case err := <-readErr:
+		if err == nil {
+			t.Fatal("expected Read to return an error, got nil")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Read blocked forever instead of giving up after migrationGraceWait")
+	}
+
+	_ = pc.Close()
+}
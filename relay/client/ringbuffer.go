@@ -0,0 +1,135 @@
+package client
+
+import "sync"
+
+// DropPolicy controls what a ringBuffer does once it is full and a new message arrives.
+type DropPolicy int
+
+const (
+	// DropOldest discards the oldest buffered message to make room for the new one.
+	DropOldest DropPolicy = iota
+	// DropNewest discards the incoming message, keeping everything already buffered.
+	DropNewest
+	// DisconnectAfterThreshold behaves like DropOldest, but once a peer has caused threshold
+	// consecutive drops the peer is disconnected instead of continuing to silently lose data.
+	DisconnectAfterThreshold
+)
+
+const defaultPeerBufferCapacity = 64
+
+// ringBuffer is a fixed-capacity, concurrency-safe queue of Msg used for per-peer delivery in
+// Client.readLoop. Unlike an unbuffered/small Go channel, pushing to a full ringBuffer never
+// blocks: depending on policy it drops the oldest or the newest message instead, so one slow
+// reader can't stall delivery to every other peer.
+type ringBuffer struct {
+	policy    DropPolicy
+	threshold int
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	buf      []Msg
+	head     int
+	size     int
+	closed   bool
+
+	dropped          uint64
+	consecutiveDrops int
+}
+
+func newRingBuffer(capacity int, policy DropPolicy, threshold int) *ringBuffer {
+	if capacity <= 0 {
+		capacity = defaultPeerBufferCapacity
+	}
+
+	rb := &ringBuffer{
+		policy:    policy,
+		threshold: threshold,
+		buf:       make([]Msg, capacity),
+	}
+	rb.notEmpty = sync.NewCond(&rb.mu)
+	return rb
+}
+
+// push enqueues msg, applying the configured DropPolicy if the buffer is full. It returns true
+// when the caller should disconnect the peer (only possible under DisconnectAfterThreshold).
+func (rb *ringBuffer) push(msg Msg) (disconnect bool) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	if rb.closed {
+		return false
+	}
+
+	if rb.size < len(rb.buf) {
+		rb.consecutiveDrops = 0
+		rb.enqueue(msg)
+		rb.notEmpty.Signal()
+		return false
+	}
+
+	rb.dropped++
+	rb.consecutiveDrops++
+
+	switch rb.policy {
+	case DropNewest:
+		return false
+	case DisconnectAfterThreshold:
+		rb.dequeueOldest()
+		rb.enqueue(msg)
+		rb.notEmpty.Signal()
+		return rb.threshold > 0 && rb.consecutiveDrops >= rb.threshold
+	default: // DropOldest
+		rb.dequeueOldest()
+		rb.enqueue(msg)
+		rb.notEmpty.Signal()
+		return false
+	}
+}
+
+// enqueue assumes the caller holds rb.mu and that there is room for one more message.
+func (rb *ringBuffer) enqueue(msg Msg) {
+	idx := (rb.head + rb.size) % len(rb.buf)
+	rb.buf[idx] = msg
+	rb.size++
+}
+
+// dequeueOldest assumes the caller holds rb.mu and that the buffer is non-empty.
+func (rb *ringBuffer) dequeueOldest() {
+	rb.buf[rb.head] = Msg{}
+	rb.head = (rb.head + 1) % len(rb.buf)
+	rb.size--
+}
+
+// pop blocks until a message is available or the buffer is closed and empty, in which case ok is
+// false. Messages still buffered when the buffer is closed are returned first.
+func (rb *ringBuffer) pop() (msg Msg, ok bool) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	for rb.size == 0 && !rb.closed {
+		rb.notEmpty.Wait()
+	}
+	if rb.size == 0 {
+		return Msg{}, false
+	}
+
+	msg = rb.buf[rb.head]
+	rb.dequeueOldest()
+	return msg, true
+}
+
+// close unblocks any pending or future pop calls; once the remaining buffered messages have been
+// popped, they return ok=false.
+func (rb *ringBuffer) close() {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	rb.closed = true
+	rb.notEmpty.Broadcast()
+}
+
+// droppedCount returns how many messages have been discarded because the buffer was full.
+func (rb *ringBuffer) droppedCount() uint64 {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	return rb.dropped
+}
@@ -0,0 +1,59 @@
+package client
+
+import "testing"
+
+// BenchmarkRingBuffer_HealthyPeer measures fan-out throughput to a single peer whose reader keeps
+// up, used as a baseline for BenchmarkRingBuffer_OneStalledPeer.
+func BenchmarkRingBuffer_HealthyPeer(b *testing.B) {
+	rb := newRingBuffer(64, DropOldest, 0)
+	go drain(rb)
+	defer rb.close()
+
+	msg := Msg{buf: make([]byte, bufferSize)}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rb.push(msg)
+	}
+}
+
+// BenchmarkRingBuffer_OneStalledPeer fans out the same volume of messages to N peer buffers, one
+// of which is never drained (the "stalled" peer). With a bounded ring buffer, push never blocks,
+// so per-message cost for the healthy peers stays roughly flat as the stalled peer fills up and
+// starts dropping, unlike the original unbounded `chan Msg <- ...` send which would block the
+// whole read loop once the stalled peer's small channel filled up.
+func BenchmarkRingBuffer_OneStalledPeer(b *testing.B) {
+	const peers = 8
+
+	buffers := make([]*ringBuffer, peers)
+	for i := range buffers {
+		buffers[i] = newRingBuffer(64, DropOldest, 0)
+		if i == 0 {
+			continue // peer 0 is the stalled one: nobody reads from it
+		}
+		go drain(buffers[i])
+	}
+	defer func() {
+		for _, rb := range buffers {
+			rb.close()
+		}
+	}()
+
+	msg := Msg{buf: make([]byte, bufferSize)}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, rb := range buffers {
+			rb.push(msg)
+		}
+	}
+}
+
+// drain pops messages from rb until it is closed.
+func drain(rb *ringBuffer) {
+	for {
+		if _, ok := rb.pop(); !ok {
+			return
+		}
+	}
+}